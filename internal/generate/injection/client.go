@@ -0,0 +1,47 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injection
+
+import "github.com/dave/jennifer/jen"
+
+// generateClient renders a client.go that injects a typed clientset for
+// the supplied Kind's API group into a context.Context.
+func generateClient(k Kind, pkgs Packages) *jen.File {
+	f := jen.NewFile("client")
+	f.HeaderComment("Code generated by crossplane-tools. DO NOT EDIT.")
+	f.PackageComment("Package client injects a typed " + k.Name + " clientset into a context.Context.")
+
+	f.Type().Id("contextKey").Struct()
+
+	f.Var().Id("key").Id("contextKey")
+
+	f.Comment("With returns a new context with the supplied clientset injected.")
+	f.Func().Id("With").Params(jen.Id("ctx").Qual("context", "Context"), jen.Id("c").Qual(pkgs.Clientset, "Interface")).Qual("context", "Context").Block(
+		jen.Return(jen.Qual("context", "WithValue").Call(jen.Id("ctx"), jen.Id("key"), jen.Id("c"))),
+	)
+
+	f.Comment("Get extracts the injected clientset from the supplied context.")
+	f.Func().Id("Get").Params(jen.Id("ctx").Qual("context", "Context")).Qual(pkgs.Clientset, "Interface").Block(
+		jen.List(jen.Id("c"), jen.Id("ok")).Op(":=").Id("ctx").Dot("Value").Call(jen.Id("key")).Assert(jen.Qual(pkgs.Clientset, "Interface")),
+		jen.If(jen.Op("!").Id("ok")).Block(
+			jen.Qual("log", "Panic").Call(jen.Lit("Unable to fetch "+k.Name+" client from context.")),
+		),
+		jen.Return(jen.Id("c")),
+	)
+
+	return f
+}
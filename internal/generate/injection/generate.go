@@ -0,0 +1,58 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injection
+
+import (
+	"path"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// Packages are the import paths that the generated injection packages are
+// thin wrappers around. Clientset is the typed clientset package that
+// client-gen produces for a Kind's API group, and Listers is the
+// group/version lister package that lister-gen produces. Informers is a
+// package whose Factory(ctx) function returns the shared informer factory
+// present in a context.Context, on which the generated informer calls
+// <Group>().<Version>().<Resource>().Informer().
+type Packages struct {
+	Clientset string
+	Informers string
+	Listers   string
+}
+
+// A File is a generated Go source file and the slash-separated path,
+// relative to the injection output root, that it should be written to.
+type File struct {
+	Path string
+	File *jen.File
+}
+
+// Generate returns the client, informer, and lister injection files for
+// the supplied Kind. base is the import path of the injection output root
+// that the generated files will be written under - it is used to let the
+// lister package import the informer package it depends on.
+func Generate(base string, k Kind, pkgs Packages) []File {
+	sub := path.Join(k.Group, k.Version, k.Resource)
+	informerPkg := path.Join(base, "informers", sub)
+
+	return []File{
+		{Path: path.Join("client", sub, "client.go"), File: generateClient(k, pkgs)},
+		{Path: path.Join("informers", sub, "informer.go"), File: generateInformer(k, pkgs)},
+		{Path: path.Join("listers", sub, "lister.go"), File: generateLister(k, pkgs, informerPkg)},
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injection
+
+import (
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+)
+
+// generateInformer renders an informer.go that injects a SharedIndexInformer
+// for the supplied Kind and registers itself with injection.Default so that
+// it is wired up automatically wherever a shared informer factory is
+// present in the context.
+func generateInformer(k Kind, pkgs Packages) *jen.File {
+	group := title(strings.Split(k.Group, ".")[0])
+	version := title(k.Version)
+
+	f := jen.NewFile("informer")
+	f.HeaderComment("Code generated by crossplane-tools. DO NOT EDIT.")
+	f.PackageComment("Package informer injects a " + k.Name + " SharedIndexInformer into a context.Context.")
+
+	f.Type().Id("contextKey").Struct()
+
+	f.Var().Id("key").Id("contextKey")
+
+	f.Comment("With returns a new context with the supplied informer injected.")
+	f.Func().Id("With").Params(
+		jen.Id("ctx").Qual("context", "Context"),
+		jen.Id("i").Qual("k8s.io/client-go/tools/cache", "SharedIndexInformer"),
+	).Qual("context", "Context").Block(
+		jen.Return(jen.Qual("context", "WithValue").Call(jen.Id("ctx"), jen.Id("key"), jen.Id("i"))),
+	)
+
+	f.Comment("Get extracts the injected SharedIndexInformer from the supplied context.")
+	f.Func().Id("Get").Params(jen.Id("ctx").Qual("context", "Context")).Qual("k8s.io/client-go/tools/cache", "SharedIndexInformer").Block(
+		jen.List(jen.Id("i"), jen.Id("ok")).Op(":=").Id("ctx").Dot("Value").Call(jen.Id("key")).Assert(jen.Qual("k8s.io/client-go/tools/cache", "SharedIndexInformer")),
+		jen.If(jen.Op("!").Id("ok")).Block(
+			jen.Qual("log", "Panic").Call(jen.Lit("Unable to fetch "+k.Name+" informer from context.")),
+		),
+		jen.Return(jen.Id("i")),
+	)
+
+	f.Comment("withInformer constructs the informer from the shared informer factory")
+	f.Comment("already present in the context and injects it.")
+	f.Func().Id("withInformer").Params(jen.Id("ctx").Qual("context", "Context")).Qual("context", "Context").Block(
+		jen.Id("i").Op(":=").Qual(pkgs.Informers, "Factory").Call(jen.Id("ctx")).Dot(group).Call().Dot(version).Call().Dot(title(k.Resource)).Call().Dot("Informer").Call(),
+		jen.Return(jen.Id("With").Call(jen.Id("ctx"), jen.Id("i"))),
+	)
+
+	f.Func().Id("init").Params().Block(
+		jen.Qual("github.com/crossplaneio/crossplane-tools/pkg/injection", "Default").Dot("RegisterInformer").Call(jen.Id("withInformer")),
+	)
+
+	return f
+}
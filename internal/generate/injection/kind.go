@@ -0,0 +1,82 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package injection generates Knative-style client, informer, and lister
+// injection packages for the Crossplane managed resource Kinds that the
+// method package's code generation already processes.
+package injection
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// A Kind is a Crossplane managed resource Kind and the Kubernetes API
+// group, version, and plural resource it is served under.
+type Kind struct {
+	Name     string
+	Group    string
+	Version  string
+	Resource string
+}
+
+// KindsFromObjects discovers the Kind/KindList pairs present in the
+// supplied types.Object set, using the same
+// strings.TrimSuffix(o.Name(), "List") convention already used by
+// method.NewSetPortableClassItems. objs is typically the same set of
+// objects the method package's Write loop iterates over for a package.
+// plural returns the lowercase plural resource name (e.g. a CRD's
+// spec.names.plural) for the supplied Kind name - naively appending "s"
+// mishandles irregular plurals such as Ingress or Policy, so callers must
+// supply the real value rather than have one guessed for them.
+func KindsFromObjects(group, version string, objs []types.Object, plural func(kind string) string) []Kind {
+	named := make(map[string]bool, len(objs))
+	for _, o := range objs {
+		named[o.Name()] = true
+	}
+
+	kinds := make([]Kind, 0)
+	for _, o := range objs {
+		if !strings.HasSuffix(o.Name(), "List") {
+			continue
+		}
+		name := strings.TrimSuffix(o.Name(), "List")
+		if !named[name] {
+			continue
+		}
+		kinds = append(kinds, Kind{
+			Name:     name,
+			Group:    group,
+			Version:  version,
+			Resource: plural(name),
+		})
+	}
+
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].Name < kinds[j].Name })
+	return kinds
+}
+
+// title upper-cases the first rune of s, e.g. to turn an informer
+// factory's lowercase group or version into the CamelCase accessor name
+// client-gen and informer-gen generate for it - "database" becomes
+// "Database", "v1alpha1" becomes "V1alpha1".
+func title(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package injection
+
+import "github.com/dave/jennifer/jen"
+
+// generateLister renders a lister.go that extracts a typed Lister for the
+// supplied Kind from the informer injected by the package at informerPkg.
+func generateLister(k Kind, pkgs Packages, informerPkg string) *jen.File {
+	lister := k.Name + "Lister"
+
+	f := jen.NewFile("lister")
+	f.HeaderComment("Code generated by crossplane-tools. DO NOT EDIT.")
+	f.PackageComment("Package lister extracts a typed " + lister + " from a context.Context.")
+
+	f.Comment("Get returns a " + lister + " backed by the informer injected into the")
+	f.Comment("supplied context.")
+	f.Func().Id("Get").Params(jen.Id("ctx").Qual("context", "Context")).Qual(pkgs.Listers, lister).Block(
+		jen.Return(jen.Qual(pkgs.Listers, "New"+lister).Call(jen.Qual(informerPkg, "Get").Call(jen.Id("ctx")).Dot("GetIndexer").Call())),
+	)
+
+	return f
+}
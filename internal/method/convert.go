@@ -0,0 +1,388 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package method
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+const pkgConversion = "sigs.k8s.io/controller-runtime/pkg/conversion"
+
+// NewConvertTo returns a Generator that writes a ConvertTo method
+// converting the receiver - a spoke type - to the hub type named hubType
+// in the package at hubPkg, satisfying
+// sigs.k8s.io/controller-runtime's conversion.Convertible.
+func NewConvertTo(hubPkg, hubType string) Generator {
+	return Generator{
+		Name: "ConvertTo",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			hub, err := loadStructType(hubPkg, hubType)
+			if err != nil {
+				f.Commentf("ConvertTo of this %s was not generated: %s", o.Name(), err)
+				return nil
+			}
+			spoke := structTypeOf(o)
+			if spoke == nil {
+				f.Commentf("ConvertTo of this %s was not generated: %s is not a struct", o.Name(), o.Name())
+				return nil
+			}
+
+			f.Commentf("ConvertTo converts this %s to the Hub, %s.", o.Name(), hubType)
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("ConvertTo").Params(jen.Id("dstRaw").Qual(pkgConversion, "Hub")).Error().Block(
+				convertToBody(f, opts.Receiver, o.Name(), spoke, hubPkg, hubType, hub)...,
+			)
+			return nil
+		},
+	}
+}
+
+// NewConvertFrom returns a Generator that writes a ConvertFrom method
+// populating the receiver - a spoke type - from the hub type named
+// hubType in the package at hubPkg, satisfying
+// sigs.k8s.io/controller-runtime's conversion.Convertible.
+func NewConvertFrom(hubPkg, hubType string) Generator {
+	return Generator{
+		Name: "ConvertFrom",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			hub, err := loadStructType(hubPkg, hubType)
+			if err != nil {
+				f.Commentf("ConvertFrom of this %s was not generated: %s", o.Name(), err)
+				return nil
+			}
+			spoke := structTypeOf(o)
+			if spoke == nil {
+				f.Commentf("ConvertFrom of this %s was not generated: %s is not a struct", o.Name(), o.Name())
+				return nil
+			}
+
+			f.Commentf("ConvertFrom converts this %s from the Hub, %s.", o.Name(), hubType)
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("ConvertFrom").Params(jen.Id("srcRaw").Qual(pkgConversion, "Hub")).Error().Block(
+				convertFromBody(f, opts.Receiver, o.Name(), spoke, hubPkg, hubType, hub)...,
+			)
+			return nil
+		},
+	}
+}
+
+func convertToBody(f *jen.File, receiver, spokeType string, spoke *types.Struct, hubPkg, hubType string, hub *types.Struct) []jen.Code {
+	stmts := []jen.Code{
+		jen.List(jen.Id("dst"), jen.Id("ok")).Op(":=").Id("dstRaw").Assert(jen.Op("*").Qual(hubPkg, hubType)),
+		jen.If(jen.Op("!").Id("ok")).Block(
+			jen.Return(jen.Qual("github.com/pkg/errors", "Errorf").Call(jen.Lit(fmt.Sprintf("dstRaw is not a *%s", hubType)))),
+		),
+	}
+	stmts = append(stmts, convertFields(f, helpersFor(f), jen.Id(receiver), jen.Id("dst"), spoke, hub, spokeType, hubType)...)
+	stmts = append(stmts, jen.Return(jen.Nil()))
+	return stmts
+}
+
+func convertFromBody(f *jen.File, receiver, spokeType string, spoke *types.Struct, hubPkg, hubType string, hub *types.Struct) []jen.Code {
+	stmts := []jen.Code{
+		jen.List(jen.Id("src"), jen.Id("ok")).Op(":=").Id("srcRaw").Assert(jen.Op("*").Qual(hubPkg, hubType)),
+		jen.If(jen.Op("!").Id("ok")).Block(
+			jen.Return(jen.Qual("github.com/pkg/errors", "Errorf").Call(jen.Lit(fmt.Sprintf("srcRaw is not a *%s", hubType)))),
+		),
+	}
+	stmts = append(stmts, convertFields(f, helpersFor(f), jen.Id("src"), jen.Id(receiver), hub, spoke, hubType, spokeType)...)
+	stmts = append(stmts, jen.Return(jen.Nil()))
+	return stmts
+}
+
+// conversionHelpers tracks, per generated jen.File, which conversion
+// helper functions have already been emitted into it. ConvertTo and
+// ConvertFrom are typically both written to the same file for the same
+// object, and must share a registry so that a field requiring a helper in
+// both directions (e.g. a nested v1alpha1.Parameters <-> v1beta1.Parameters
+// struct) only has each direction's helper emitted once.
+var conversionHelpers = map[*jen.File]map[string]bool{}
+
+// helpersFor returns the helper-name registry for the supplied file,
+// creating one if this is the first Generator to write to it.
+func helpersFor(f *jen.File) map[string]bool {
+	h, ok := conversionHelpers[f]
+	if !ok {
+		h = map[string]bool{}
+		conversionHelpers[f] = h
+	}
+	return h
+}
+
+// convertFields walks from's fields in lexical order and, for each one
+// that also exists on to, emits code copying the value across. from and to
+// are jen expressions (e.g. the receiver or dst/src identifiers) that the
+// field selectors are appended to.
+func convertFields(f *jen.File, helpers map[string]bool, from, to *jen.Statement, fromType, toType *types.Struct, fromName, toName string) []jen.Code {
+	names := fieldNames(fromType)
+	stmts := make([]jen.Code, 0, len(names))
+
+	for _, name := range names {
+		ff := fieldByName(fromType, name)
+		tf := fieldByName(toType, name)
+		if tf == nil {
+			stmts = append(stmts, jen.Comment(fmt.Sprintf("TODO(conversion): %s.%s has no equivalent in %s.", fromName, name, toName)))
+			continue
+		}
+
+		fromSel := jen.Add(from.Clone()).Dot(name)
+		toSel := jen.Add(to.Clone()).Dot(name)
+
+		if c := convertField(f, helpers, fromSel, toSel, ff.Type(), tf.Type(), name); c != nil {
+			stmts = append(stmts, c...)
+			continue
+		}
+
+		stmts = append(stmts, jen.Comment(fmt.Sprintf("TODO(conversion): %s.%s and %s.%s differ in type.", fromName, name, toName, name)))
+	}
+
+	return stmts
+}
+
+// convertField returns the statements that copy fromSel into toSel, where
+// fromSel and toSel are jen selector expressions (e.g. src.Field) of types
+// fromT and toT respectively. It returns nil if it does not know how to
+// convert between fromT and toT, in which case the caller should fall back
+// to a TODO comment.
+func convertField(f *jen.File, helpers map[string]bool, fromSel, toSel *jen.Statement, fromT, toT types.Type, field string) []jen.Code {
+	if types.Identical(fromT, toT) {
+		return []jen.Code{jen.Add(toSel).Op("=").Add(fromSel)}
+	}
+
+	if sameUnderlying(fromT, toT) {
+		helper := convertHelperName(fromT, toT, field)
+		if !helpers[helper] {
+			helpers[helper] = true
+			emitConvertHelper(f, helper, fromT, toT)
+		}
+		return []jen.Code{jen.Add(toSel).Op("=").Id(helper).Call(jen.Add(fromSel))}
+	}
+
+	fp, fpOK := fromT.(*types.Pointer)
+	tp, tpOK := toT.(*types.Pointer)
+	if fpOK && tpOK {
+		elem, ok := convertElem(f, helpers, jen.Op("*").Add(fromSel.Clone()), fp.Elem(), tp.Elem(), field)
+		if !ok {
+			return nil
+		}
+		tmp := "converted" + field
+		return []jen.Code{
+			jen.If(jen.Add(fromSel.Clone()).Op("!=").Nil()).Block(
+				jen.Id(tmp).Op(":=").Add(elem),
+				jen.Add(toSel).Op("=").Op("&").Id(tmp),
+			),
+		}
+	}
+
+	fs, fsOK := fromT.(*types.Slice)
+	ts, tsOK := toT.(*types.Slice)
+	if fsOK && tsOK {
+		elem, ok := convertElem(f, helpers, jen.Id("v"), fs.Elem(), ts.Elem(), field)
+		if !ok {
+			return nil
+		}
+		return []jen.Code{
+			jen.If(jen.Add(fromSel.Clone()).Op("!=").Nil()).Block(
+				jen.Add(toSel.Clone()).Op("=").Make(typeCode(toT), jen.Len(fromSel.Clone())),
+				jen.For(jen.List(jen.Id("i"), jen.Id("v")).Op(":=").Range().Add(fromSel.Clone())).Block(
+					jen.Add(toSel.Clone()).Index(jen.Id("i")).Op("=").Add(elem),
+				),
+			),
+		}
+	}
+
+	fm, fmOK := fromT.(*types.Map)
+	tm, tmOK := toT.(*types.Map)
+	if fmOK && tmOK && types.Identical(fm.Key(), tm.Key()) {
+		elem, ok := convertElem(f, helpers, jen.Id("v"), fm.Elem(), tm.Elem(), field)
+		if !ok {
+			return nil
+		}
+		return []jen.Code{
+			jen.If(jen.Add(fromSel.Clone()).Op("!=").Nil()).Block(
+				jen.Add(toSel.Clone()).Op("=").Make(typeCode(toT), jen.Len(fromSel.Clone())),
+				jen.For(jen.List(jen.Id("k"), jen.Id("v")).Op(":=").Range().Add(fromSel.Clone())).Block(
+					jen.Add(toSel.Clone()).Index(jen.Id("k")).Op("=").Add(elem),
+				),
+			),
+		}
+	}
+
+	return nil
+}
+
+// convertElem returns a jen expression converting expr (of type fromT)
+// into a value of type toT, either unchanged, via an identically-named
+// helper, or ok=false if fromT and toT are not convertible.
+func convertElem(f *jen.File, helpers map[string]bool, expr *jen.Statement, fromT, toT types.Type, field string) (*jen.Statement, bool) {
+	if types.Identical(fromT, toT) {
+		return expr, true
+	}
+	if sameUnderlying(fromT, toT) {
+		helper := convertHelperName(fromT, toT, field)
+		if !helpers[helper] {
+			helpers[helper] = true
+			emitConvertHelper(f, helper, fromT, toT)
+		}
+		return jen.Id(helper).Call(expr), true
+	}
+	return nil, false
+}
+
+// emitConvertHelper writes a package-level function that converts a value
+// of type from to a value of type to, where both share an identical
+// underlying type but are named differently (e.g. a struct or enum-like
+// typedef defined separately in two API versions). The helper is only
+// written once per generated file; callers must track that themselves via
+// a helpers registry.
+func emitConvertHelper(f *jen.File, name string, from, to types.Type) {
+	f.Commentf("%s converts between identically shaped named types from different packages.", name)
+	f.Func().Id(name).Params(jen.Id("in").Add(typeCode(from))).Add(typeCode(to)).Block(
+		jen.Id("out").Op(":=").Add(typeCode(to)).Call(jen.Id("in")),
+		jen.Return(jen.Id("out")),
+	)
+}
+
+// convertHelperName derives a helper function name from the full package
+// path of fromT and toT rather than their bare type names, so that
+// converting identically-named types in two different directions (e.g.
+// ConvertTo's v1alpha1.Parameters -> v1beta1.Parameters and ConvertFrom's
+// v1beta1.Parameters -> v1alpha1.Parameters) never collide on the same
+// name despite sharing a file-wide helpers registry.
+func convertHelperName(fromT, toT types.Type, field string) string {
+	return fmt.Sprintf("convert_%s_To_%s_%s", namedIdent(fromT), namedIdent(toT), field)
+}
+
+// namedIdent returns a package-path-qualified identifier for a named type,
+// e.g. "github_com_crossplaneio_crossplane_apis_compute_v1alpha1_Parameters".
+func namedIdent(t types.Type) string {
+	n, ok := t.(*types.Named)
+	if !ok {
+		return t.String()
+	}
+	pkg := n.Obj().Pkg()
+	if pkg == nil {
+		return n.Obj().Name()
+	}
+	return sanitizeIdent(pkg.Path()) + "_" + n.Obj().Name()
+}
+
+// sanitizeIdent replaces runes that cannot appear in a Go identifier with
+// underscores.
+func sanitizeIdent(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// typeCode renders a jen expression for the supplied type. It only needs to
+// handle the simple named, pointer, slice, and map shapes that appear in
+// Crossplane API types.
+func typeCode(t types.Type) *jen.Statement {
+	switch v := t.(type) {
+	case *types.Pointer:
+		return jen.Op("*").Add(typeCode(v.Elem()))
+	case *types.Slice:
+		return jen.Index().Add(typeCode(v.Elem()))
+	case *types.Map:
+		return jen.Map(typeCode(v.Key())).Add(typeCode(v.Elem()))
+	case *types.Named:
+		if pkg := v.Obj().Pkg(); pkg != nil {
+			return jen.Qual(pkg.Path(), v.Obj().Name())
+		}
+		return jen.Id(v.Obj().Name())
+	default:
+		return jen.Id(t.String())
+	}
+}
+
+func fieldNames(s *types.Struct) []string {
+	names := make([]string, 0, s.NumFields())
+	for i := 0; i < s.NumFields(); i++ {
+		names = append(names, s.Field(i).Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func fieldByName(s *types.Struct, name string) *types.Var {
+	for i := 0; i < s.NumFields(); i++ {
+		if s.Field(i).Name() == name {
+			return s.Field(i)
+		}
+	}
+	return nil
+}
+
+// sameUnderlying returns true if a and b are both named types, declared in
+// different packages, with identical underlying types - i.e. they are the
+// same struct, or the same enum-like typedef (e.g. a ReclaimPolicy string
+// alias), defined separately in two different API version packages.
+func sameUnderlying(a, b types.Type) bool {
+	na, ok := a.(*types.Named)
+	if !ok {
+		return false
+	}
+	nb, ok := b.(*types.Named)
+	if !ok {
+		return false
+	}
+	if na.Obj().Pkg() == nb.Obj().Pkg() {
+		return false
+	}
+	return types.Identical(na.Underlying(), nb.Underlying())
+}
+
+// structTypeOf returns the underlying struct type of the supplied Object,
+// which is assumed to be a named struct type.
+func structTypeOf(o types.Object) *types.Struct {
+	s, _ := o.Type().Underlying().(*types.Struct)
+	return s
+}
+
+// loadStructType loads the package at importPath and returns the
+// underlying struct type of the named type it declares with the supplied
+// name.
+func loadStructType(importPath, name string) (*types.Struct, error) {
+	cfg := &packages.Config{Mode: packages.NeedTypes | packages.NeedTypesInfo | packages.NeedDeps}
+	pkgs, err := packages.Load(cfg, importPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot load %s", importPath)
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, errors.Errorf("%d error(s) loading %s", n, importPath)
+	}
+	for _, p := range pkgs {
+		o := p.Types.Scope().Lookup(name)
+		if o == nil {
+			continue
+		}
+		if s, ok := o.Type().Underlying().(*types.Struct); ok {
+			return s, nil
+		}
+	}
+	return nil, errors.Errorf("no struct named %s found in %s", name, importPath)
+}
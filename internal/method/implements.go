@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package method
+
+import (
+	"go/types"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+// Implements returns a Filter that returns true (i.e. skips generation) if
+// the supplied object's pointer type already satisfies the supplied
+// interface. This is useful when a type satisfies an interface by embedding
+// a struct that provides the required methods, in which case the
+// filename-based DefinedOutside filter would not detect that the method is
+// already implemented.
+func Implements(iface *types.Interface) Filter {
+	return func(o types.Object, _ string) bool {
+		return types.Implements(types.NewPointer(o.Type()), iface)
+	}
+}
+
+// LoadInterface loads the package at the supplied import path and returns
+// the named interface type it declares with the supplied name. It is
+// typically used to load Crossplane runtime interfaces (e.g.
+// resource.Managed, resource.Claim, resource.PortableClass) for use with
+// Implements.
+func LoadInterface(importPath, name string) (*types.Interface, error) {
+	cfg := &packages.Config{Mode: packages.NeedTypes | packages.NeedTypesInfo | packages.NeedDeps}
+	pkgs, err := packages.Load(cfg, importPath)
+	if err != nil {
+		return nil, err
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, errors.Errorf("%d error(s) loading %s", n, importPath)
+	}
+	for _, p := range pkgs {
+		o := p.Types.Scope().Lookup(name)
+		if o == nil {
+			continue
+		}
+		if iface, ok := o.Type().Underlying().(*types.Interface); ok {
+			return iface, nil
+		}
+	}
+	return nil, errors.Errorf("no interface named %s found in %s", name, importPath)
+}
+
+// Any returns a Filter that returns true if any of the supplied Filters
+// return true.
+func Any(fs ...Filter) Filter {
+	return func(o types.Object, name string) bool {
+		for _, f := range fs {
+			if f(o, name) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// All returns a Filter that returns true only if all of the supplied
+// Filters return true.
+func All(fs ...Filter) Filter {
+	return func(o types.Object, name string) bool {
+		for _, f := range fs {
+			if !f(o, name) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -18,39 +18,161 @@ limitations under the License.
 package method
 
 import (
+	"fmt"
 	"go/token"
 	"go/types"
 	"sort"
 	"strings"
 
 	"github.com/dave/jennifer/jen"
+	"github.com/pkg/errors"
 
 	"github.com/crossplaneio/crossplane-tools/internal/fields"
 )
 
-// New is a function that adds a method on the supplied object in the
-// supplied file.
-type New func(f *jen.File, o types.Object)
+// GeneratorOptions configure the method emitted by a Generator's Run
+// function. They are supplied by the Set that runs the Generator rather
+// than baked in when the Generator is constructed, so that a given
+// Generator may be reused across objects with different receivers or
+// import paths.
+type GeneratorOptions struct {
+	// Receiver is the name given to the method receiver, e.g. "m" for
+	// "func (m *MyType) SetConditions(...)".
+	Receiver string
+
+	// Runtime is the import path of the Crossplane runtime package that
+	// supplies types such as Condition and ReclaimPolicy.
+	Runtime string
+
+	// Core is the import path of the Kubernetes core API package that
+	// supplies types such as ObjectReference.
+	Core string
+
+	// Resource is the import path of the Crossplane resource package that
+	// supplies types such as PortableClass.
+	Resource string
 
-// A Set is a map of method names to the New functions that produce
-// them.
-type Set map[string]New
+	// FieldPath is the name of the field a Generator should read from or
+	// write to, for Generators whose target field varies between types -
+	// typically Spec or SpecTemplate.
+	FieldPath string
 
-// Write the method Set for the supplied Object to the supplied file. Methods
-// are filtered by the supplied Filter.
-func (s Set) Write(f *jen.File, o types.Object, mf Filter) {
+	// BuildTags, if non-empty, are applied by NewFile as a build
+	// constraint (the `+build` line) on the generated file.
+	BuildTags []string
+}
+
+// NewFile returns a new jen.File for the supplied package name, with the
+// supplied GeneratorOptions' BuildTags applied as a build constraint. It
+// should be used in place of jen.NewFile when BuildTags are set, since a
+// build constraint is a per-file concern and must only be written once -
+// Set.Write may be called many times against the same file, once per
+// object.
+func NewFile(pkg string, opts GeneratorOptions) *jen.File {
+	f := jen.NewFile(pkg)
+	if len(opts.BuildTags) > 0 {
+		f.HeaderComment(fmt.Sprintf("+build %s", strings.Join(opts.BuildTags, ",")))
+	}
+	return f
+}
+
+// A Generator emits a method (or set of methods) for a Go type. Name
+// identifies the Generator within a Set. Requires and Produces name
+// arbitrary dependencies - typically method or field names - that a Set
+// uses to order Generators so that, for example, a Generator that relies
+// on another having already populated a field runs after it.
+type Generator struct {
+	Name     string
+	Requires []string
+	Produces []string
+	Run      func(f *jen.File, o types.Object, opts GeneratorOptions) error
+}
+
+// A Set is a collection of Generators, keyed by Name.
+type Set map[string]Generator
+
+// Write the Set's Generators for the supplied Object to the supplied file,
+// in dependency order per each Generator's Requires and Produces (see
+// order). Generators are filtered by the supplied Filter.
+func (s Set) Write(f *jen.File, o types.Object, mf Filter, opts GeneratorOptions) error {
+	order, err := s.order()
+	if err != nil {
+		return errors.Wrap(err, "cannot order generators")
+	}
+
+	for _, name := range order {
+		if mf(o, name) {
+			continue
+		}
+		if err := s[name].Run(f, o, opts); err != nil {
+			return errors.Wrap(err, name)
+		}
+	}
+	return nil
+}
+
+// order returns the Set's Generator names, topologically sorted so that a
+// Generator appears after every other Generator in the Set whose Produces
+// it Requires. Generators are visited alphabetically and each is emitted
+// right after the Generators it depends on, so a Set with no Requires is
+// ordered by name, matching the Set's previous sort-by-name behaviour. A
+// dependency is moved ahead of its dependant, and thus ahead of any
+// unrelated Generators whose names sort between them.
+func (s Set) order() ([]string, error) {
 	names := make([]string, 0, len(s))
 	for name := range s {
 		names = append(names, name)
 	}
 	sort.Strings(names)
 
+	producedBy := map[string]string{}
 	for _, name := range names {
-		if mf(o, name) {
-			continue
+		for _, p := range s[name].Produces {
+			producedBy[p] = name
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(s))
+	order := make([]string, 0, len(s))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("generator %q is part of a Requires/Produces cycle", name)
+		}
+		state[name] = visiting
+
+		reqs := append([]string{}, s[name].Requires...)
+		sort.Strings(reqs)
+		for _, r := range reqs {
+			dep, ok := producedBy[r]
+			if !ok || dep == name {
+				continue
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
 		}
-		s[name](f, o)
 	}
+	return order, nil
 }
 
 // A Filter is a function that determines whether a method should be written for
@@ -77,218 +199,296 @@ func DefinedOutside(fs *token.FileSet, filename string) Filter {
 	}
 }
 
-// NewSetConditions returns a NewMethod that writes a SetConditions method for
-// the supplied Object to the supplied file.
-func NewSetConditions(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("SetConditions of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetConditions").Params(jen.Id("c").Op("...").Qual(runtime, "Condition")).Block(
-			jen.Id(receiver).Dot(fields.NameStatus).Dot("SetConditions").Call(jen.Id("c").Op("...")),
-		)
+// NewSetConditions returns a Generator that writes a SetConditions method
+// for the supplied Object to the supplied file.
+func NewSetConditions() Generator {
+	return Generator{
+		Name: "SetConditions",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("SetConditions of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("SetConditions").Params(jen.Id("c").Op("...").Qual(opts.Runtime, "Condition")).Block(
+				jen.Id(opts.Receiver).Dot(fields.NameStatus).Dot("SetConditions").Call(jen.Id("c").Op("...")),
+			)
+			return nil
+		},
 	}
 }
 
-// NewGetCondition returns a NewMethod that writes a GetCondition method for
+// NewGetCondition returns a Generator that writes a GetCondition method for
 // the supplied Object to the supplied file.
-func NewGetCondition(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("GetCondition of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetCondition").Params(jen.Id("c").Op("...").Qual(runtime, "Condition")).Block(
-			jen.Id(receiver).Dot(fields.NameStatus).Dot("GetCondition").Call(jen.Id("c").Op("...")),
-		)
+func NewGetCondition() Generator {
+	return Generator{
+		Name: "GetCondition",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("GetCondition of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("GetCondition").Params(jen.Id("c").Op("...").Qual(opts.Runtime, "Condition")).Block(
+				jen.Id(opts.Receiver).Dot(fields.NameStatus).Dot("GetCondition").Call(jen.Id("c").Op("...")),
+			)
+			return nil
+		},
 	}
 }
 
-// NewSetBindingPhase returns a NewMethod that writes a SetBindingPhase method
-// for the supplied Object to the supplied file.
-func NewSetBindingPhase(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("SetBindingPhase of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetBindingPhase").Params(jen.Id("p").Qual(runtime, "BindingPhase")).Block(
-			jen.Id(receiver).Dot(fields.NameStatus).Dot("SetBindingPhase").Call(jen.Id("p")),
-		)
+// NewSetBindingPhase returns a Generator that writes a SetBindingPhase
+// method for the supplied Object to the supplied file.
+func NewSetBindingPhase() Generator {
+	return Generator{
+		Name: "SetBindingPhase",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("SetBindingPhase of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("SetBindingPhase").Params(jen.Id("p").Qual(opts.Runtime, "BindingPhase")).Block(
+				jen.Id(opts.Receiver).Dot(fields.NameStatus).Dot("SetBindingPhase").Call(jen.Id("p")),
+			)
+			return nil
+		},
 	}
 }
 
-// NewGetBindingPhase returns a NewMethod that writes a GetBindingPhase method
-// for the supplied Object to the supplied file.
-func NewGetBindingPhase(receiver, runtime string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("GetBindingPhase of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetBindingPhase").Params().Qual(runtime, "BindingPhase").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameStatus).Dot("GetBindingPhase").Call()),
-		)
+// NewGetBindingPhase returns a Generator that writes a GetBindingPhase
+// method for the supplied Object to the supplied file.
+func NewGetBindingPhase() Generator {
+	return Generator{
+		Name: "GetBindingPhase",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("GetBindingPhase of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("GetBindingPhase").Params().Qual(opts.Runtime, "BindingPhase").Block(
+				jen.Return(jen.Id(opts.Receiver).Dot(fields.NameStatus).Dot("GetBindingPhase").Call()),
+			)
+			return nil
+		},
 	}
 }
 
-// NewSetClaimReference returns a NewMethod that writes a SetClaimReference
+// NewSetClaimReference returns a Generator that writes a SetClaimReference
 // method for the supplied Object to the supplied file.
-func NewSetClaimReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("SetClaimReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetClaimReference").Params(jen.Id("r").Op("*").Qual(core, "ObjectReference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("ClaimReference").Op("=").Id("r"),
-		)
+func NewSetClaimReference() Generator {
+	return Generator{
+		Name: "SetClaimReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("SetClaimReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("SetClaimReference").Params(jen.Id("r").Op("*").Qual(opts.Core, "ObjectReference")).Block(
+				jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("ClaimReference").Op("=").Id("r"),
+			)
+			return nil
+		},
 	}
 }
 
-// NewGetClaimReference returns a NewMethod that writes a GetClaimReference
+// NewGetClaimReference returns a Generator that writes a GetClaimReference
 // method for the supplied Object to the supplied file.
-func NewGetClaimReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("GetClaimReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetClaimReference").Params().Op("*").Qual(core, "ObjectReference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("ClaimReference")),
-		)
+func NewGetClaimReference() Generator {
+	return Generator{
+		Name: "GetClaimReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("GetClaimReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("GetClaimReference").Params().Op("*").Qual(opts.Core, "ObjectReference").Block(
+				jen.Return(jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("ClaimReference")),
+			)
+			return nil
+		},
 	}
 }
 
-// NewSetResourceReference returns a NewMethod that writes a
-// SetResourceReference method for the supplied Object to the supplied file.
-func NewSetResourceReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("SetResourceReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetResourceReference").Params(jen.Id("r").Op("*").Qual(core, "ObjectReference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("ResourceReference").Op("=").Id("r"),
-		)
+// NewSetResourceReference returns a Generator that writes a
+// SetResourceReference method for the supplied Object to the supplied
+// file.
+func NewSetResourceReference() Generator {
+	return Generator{
+		Name: "SetResourceReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("SetResourceReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("SetResourceReference").Params(jen.Id("r").Op("*").Qual(opts.Core, "ObjectReference")).Block(
+				jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("ResourceReference").Op("=").Id("r"),
+			)
+			return nil
+		},
 	}
 }
 
-// NewGetResourceReference returns a NewMethod that writes a
-// GetResourceReference method for the supplied Object to the supplied file.
-func NewGetResourceReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("GetResourceReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetResourceReference").Params().Op("*").Qual(core, "ObjectReference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("ResourceReference")),
-		)
+// NewGetResourceReference returns a Generator that writes a
+// GetResourceReference method for the supplied Object to the supplied
+// file.
+func NewGetResourceReference() Generator {
+	return Generator{
+		Name: "GetResourceReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("GetResourceReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("GetResourceReference").Params().Op("*").Qual(opts.Core, "ObjectReference").Block(
+				jen.Return(jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("ResourceReference")),
+			)
+			return nil
+		},
 	}
 }
 
-// NewSetNonPortableClassReference returns a NewMethod that writes a
-// SetNonPortableClassReference method for the supplied Object to the supplied
-// file.
-func NewSetNonPortableClassReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("SetNonPortableClassReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetNonPortableClassReference").Params(jen.Id("r").Op("*").Qual(core, "ObjectReference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("NonPortableClassReference").Op("=").Id("r"),
-		)
+// NewSetNonPortableClassReference returns a Generator that writes a
+// SetNonPortableClassReference method for the supplied Object to the
+// supplied file.
+func NewSetNonPortableClassReference() Generator {
+	return Generator{
+		Name: "SetNonPortableClassReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("SetNonPortableClassReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("SetNonPortableClassReference").Params(jen.Id("r").Op("*").Qual(opts.Core, "ObjectReference")).Block(
+				jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("NonPortableClassReference").Op("=").Id("r"),
+			)
+			return nil
+		},
 	}
 }
 
-// NewGetNonPortableClassReference returns a NewMethod that writes a
-// GetNonPortableClassReference method for the supplied Object to the supplied
-// file.
-func NewGetNonPortableClassReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("GetNonPortableClassReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetNonPortableClassReference").Params().Op("*").Qual(core, "ObjectReference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("NonPortableClassReference")),
-		)
+// NewGetNonPortableClassReference returns a Generator that writes a
+// GetNonPortableClassReference method for the supplied Object to the
+// supplied file.
+func NewGetNonPortableClassReference() Generator {
+	return Generator{
+		Name: "GetNonPortableClassReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("GetNonPortableClassReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("GetNonPortableClassReference").Params().Op("*").Qual(opts.Core, "ObjectReference").Block(
+				jen.Return(jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("NonPortableClassReference")),
+			)
+			return nil
+		},
 	}
 }
 
-// NewSetPortableClassReference returns a NewMethod that writes a
-// SetPortableClassReference method for the supplied Object to the supplied
-// file.
-func NewSetPortableClassReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("SetPortableClassReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetPortableClassReference").Params(jen.Id("r").Op("*").Qual(core, "LocalObjectReference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("PortableClassReference").Op("=").Id("r"),
-		)
+// NewSetPortableClassReference returns a Generator that writes a
+// SetPortableClassReference method for the supplied Object to the
+// supplied file.
+func NewSetPortableClassReference() Generator {
+	return Generator{
+		Name: "SetPortableClassReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("SetPortableClassReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("SetPortableClassReference").Params(jen.Id("r").Op("*").Qual(opts.Core, "LocalObjectReference")).Block(
+				jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("PortableClassReference").Op("=").Id("r"),
+			)
+			return nil
+		},
 	}
 }
 
-// NewGetPortableClassReference returns a NewMethod that writes a
-// GetPortableClassReference method for the supplied Object to the supplied
-// file.
-func NewGetPortableClassReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("GetPortableClassReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetPortableClassReference").Params().Op("*").Qual(core, "LocalObjectReference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("PortableClassReference")),
-		)
+// NewGetPortableClassReference returns a Generator that writes a
+// GetPortableClassReference method for the supplied Object to the
+// supplied file.
+func NewGetPortableClassReference() Generator {
+	return Generator{
+		Name: "GetPortableClassReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("GetPortableClassReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("GetPortableClassReference").Params().Op("*").Qual(opts.Core, "LocalObjectReference").Block(
+				jen.Return(jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("PortableClassReference")),
+			)
+			return nil
+		},
 	}
 }
 
-// NewSetWriteConnectionSecretToReference returns a NewMethod that writes a
-// SetWriteConnectionSecretToReference method for the supplied Object to the
-// supplied file.
-func NewSetWriteConnectionSecretToReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("SetWriteConnectionSecretToReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetWriteConnectionSecretToReference").Params(jen.Id("r").Qual(core, "LocalObjectReference")).Block(
-			jen.Id(receiver).Dot(fields.NameSpec).Dot("WriteConnectionSecretToReference").Op("=").Id("r"),
-		)
+// NewSetWriteConnectionSecretToReference returns a Generator that writes a
+// SetWriteConnectionSecretToReference method for the supplied Object to
+// the supplied file.
+func NewSetWriteConnectionSecretToReference() Generator {
+	return Generator{
+		Name: "SetWriteConnectionSecretToReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("SetWriteConnectionSecretToReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("SetWriteConnectionSecretToReference").Params(jen.Id("r").Qual(opts.Core, "LocalObjectReference")).Block(
+				jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("WriteConnectionSecretToReference").Op("=").Id("r"),
+			)
+			return nil
+		},
 	}
 }
 
-// NewGetWriteConnectionSecretToReference returns a NewMethod that writes a
-// GetWriteConnectionSecretToReference method for the supplied Object to the
-// supplied file.
-func NewGetWriteConnectionSecretToReference(receiver, core string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("GetWriteConnectionSecretToReference of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetWriteConnectionSecretToReference").Params().Qual(core, "LocalObjectReference").Block(
-			jen.Return(jen.Id(receiver).Dot(fields.NameSpec).Dot("WriteConnectionSecretToReference")),
-		)
+// NewGetWriteConnectionSecretToReference returns a Generator that writes a
+// GetWriteConnectionSecretToReference method for the supplied Object to
+// the supplied file.
+func NewGetWriteConnectionSecretToReference() Generator {
+	return Generator{
+		Name: "GetWriteConnectionSecretToReference",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("GetWriteConnectionSecretToReference of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("GetWriteConnectionSecretToReference").Params().Qual(opts.Core, "LocalObjectReference").Block(
+				jen.Return(jen.Id(opts.Receiver).Dot(fields.NameSpec).Dot("WriteConnectionSecretToReference")),
+			)
+			return nil
+		},
 	}
 }
 
-// NewSetReclaimPolicy returns a NewMethod that writes a SetReclaimPolicy method
-// for the supplied Object to the supplied file. The ReclaimPolicy is set in the
-// supplied field - typically Spec or SpecTemplate.
-func NewSetReclaimPolicy(receiver, core, field string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("SetReclaimPolicy of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetReclaimPolicy").Params(jen.Id("r").Qual(core, "ReclaimPolicy")).Block(
-			jen.Id(receiver).Dot(field).Dot("ReclaimPolicy").Op("=").Id("r"),
-		)
+// NewSetReclaimPolicy returns a Generator that writes a SetReclaimPolicy
+// method for the supplied Object to the supplied file. The ReclaimPolicy
+// is set on the GeneratorOptions.FieldPath field - typically Spec or
+// SpecTemplate.
+func NewSetReclaimPolicy() Generator {
+	return Generator{
+		Name: "SetReclaimPolicy",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("SetReclaimPolicy of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("SetReclaimPolicy").Params(jen.Id("r").Qual(opts.Runtime, "ReclaimPolicy")).Block(
+				jen.Id(opts.Receiver).Dot(opts.FieldPath).Dot("ReclaimPolicy").Op("=").Id("r"),
+			)
+			return nil
+		},
 	}
 }
 
-// NewGetReclaimPolicy returns a NewMethod that writes a GetReclaimPolicy method
-// for the supplied Object to the supplied file. The ReclaimPolicy is returned
-// from the supplied field - typically Spec or SpecTemplate.
-func NewGetReclaimPolicy(receiver, runtime, field string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("GetReclaimPolicy of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetReclaimPolicy").Params().Qual(runtime, "ReclaimPolicy").Block(
-			jen.Return(jen.Id(receiver).Dot(field).Dot("ReclaimPolicy")),
-		)
+// NewGetReclaimPolicy returns a Generator that writes a GetReclaimPolicy
+// method for the supplied Object to the supplied file. The ReclaimPolicy
+// is read from the GeneratorOptions.FieldPath field - typically Spec or
+// SpecTemplate.
+func NewGetReclaimPolicy() Generator {
+	return Generator{
+		Name: "GetReclaimPolicy",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("GetReclaimPolicy of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("GetReclaimPolicy").Params().Qual(opts.Runtime, "ReclaimPolicy").Block(
+				jen.Return(jen.Id(opts.Receiver).Dot(opts.FieldPath).Dot("ReclaimPolicy")),
+			)
+			return nil
+		},
 	}
 }
 
-// NewSetPortableClassItems returns a NewMethod that writes a
-// SetPortableClassItems method for the supplied Object to the supplied file.
-func NewSetPortableClassItems(receiver, resource string) New {
-	return func(f *jen.File, o types.Object) {
-		element := strings.TrimSuffix(o.Name(), "List")
-		f.Commentf("SetPortableClassItems of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("SetPortableClassItems").Params(jen.Id("i").Index().Qual(resource, "PortableClass")).Block(
-			jen.Id(receiver).Dot("Items").Op("=").Make(jen.Index().Id(element), jen.Id("0"), jen.Len(jen.Id("i"))),
-			jen.For(jen.Id("j").Op(":=").Range().Id("i")).Block(
-				jen.If(jen.List(jen.Id("actual"), jen.Id("ok")).Op(":=").Id("i").Index(jen.Id("j")).Assert(jen.Op("*").Id(element)), jen.Id("ok")).Block(
-					jen.Id(receiver).Dot("Items").Op("=").Append(jen.Id(receiver).Dot("Items"), jen.Op("*").Id("actual")),
+// NewSetPortableClassItems returns a Generator that writes a
+// SetPortableClassItems method for the supplied Object to the supplied
+// file.
+func NewSetPortableClassItems() Generator {
+	return Generator{
+		Name: "SetPortableClassItems",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			element := strings.TrimSuffix(o.Name(), "List")
+			f.Commentf("SetPortableClassItems of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("SetPortableClassItems").Params(jen.Id("i").Index().Qual(opts.Resource, "PortableClass")).Block(
+				jen.Id(opts.Receiver).Dot("Items").Op("=").Make(jen.Index().Id(element), jen.Id("0"), jen.Len(jen.Id("i"))),
+				jen.For(jen.Id("j").Op(":=").Range().Id("i")).Block(
+					jen.If(jen.List(jen.Id("actual"), jen.Id("ok")).Op(":=").Id("i").Index(jen.Id("j")).Assert(jen.Op("*").Id(element)), jen.Id("ok")).Block(
+						jen.Id(opts.Receiver).Dot("Items").Op("=").Append(jen.Id(opts.Receiver).Dot("Items"), jen.Op("*").Id("actual")),
+					),
 				),
-			),
-		)
+			)
+			return nil
+		},
 	}
 }
 
-// NewGetPortableClassItems returns a NewMethod that writes a
-// GetPortableClassItems method for the supplied Object to the supplied file.
-func NewGetPortableClassItems(receiver, resource string) New {
-	return func(f *jen.File, o types.Object) {
-		f.Commentf("GetPortableClassItems of this %s.", o.Name())
-		f.Func().Params(jen.Id(receiver).Op("*").Id(o.Name())).Id("GetPortableClassItems").Params().Index().Qual(resource, "PortableClass").Block(
-			jen.Id("items").Op(":=").Make(jen.Index().Qual(resource, "PortableClass"), jen.Len(jen.Id(receiver).Dot("Items"))),
-			jen.For(jen.Id("i").Op(":=").Range().Id(receiver).Dot("Items")).Block(
-				jen.Id("items").Index(jen.Id("i")).Op("=").Qual(resource, "PortableClass").Call(jen.Op("&").Id(receiver).Dot("Items").Index(jen.Id("i"))),
-			),
-			jen.Return(jen.Id("items")),
-		)
+// NewGetPortableClassItems returns a Generator that writes a
+// GetPortableClassItems method for the supplied Object to the supplied
+// file.
+func NewGetPortableClassItems() Generator {
+	return Generator{
+		Name: "GetPortableClassItems",
+		Run: func(f *jen.File, o types.Object, opts GeneratorOptions) error {
+			f.Commentf("GetPortableClassItems of this %s.", o.Name())
+			f.Func().Params(jen.Id(opts.Receiver).Op("*").Id(o.Name())).Id("GetPortableClassItems").Params().Index().Qual(opts.Resource, "PortableClass").Block(
+				jen.Id("items").Op(":=").Make(jen.Index().Qual(opts.Resource, "PortableClass"), jen.Len(jen.Id(opts.Receiver).Dot("Items"))),
+				jen.For(jen.Id("i").Op(":=").Range().Id(opts.Receiver).Dot("Items")).Block(
+					jen.Id("items").Index(jen.Id("i")).Op("=").Qual(opts.Resource, "PortableClass").Call(jen.Op("&").Id(opts.Receiver).Dot("Items").Index(jen.Id("i"))),
+				),
+				jen.Return(jen.Id("items")),
+			)
+			return nil
+		},
 	}
 }
@@ -0,0 +1,54 @@
+/*
+Copyright 2019 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package injection provides a small registry that generated informer
+// packages use to wire themselves into a shared informer factory without
+// the consumer of those packages needing to know each one exists, mirroring
+// the pattern used by knative.dev/pkg/injection.
+package injection
+
+import "context"
+
+// An InformerInjector adds a SharedIndexInformer getter to the supplied
+// context, typically by pulling a shared informer factory that is already
+// present in the context and calling Informer() on it.
+type InformerInjector func(ctx context.Context) context.Context
+
+// Interface is satisfied by a registry that generated informer packages
+// register themselves with, typically from an init function.
+type Interface interface {
+	// RegisterInformer adds the supplied InformerInjector to the registry.
+	RegisterInformer(ii InformerInjector)
+
+	// Informers returns all InformerInjectors currently registered.
+	Informers() []InformerInjector
+}
+
+type registry struct {
+	informers []InformerInjector
+}
+
+func (r *registry) RegisterInformer(ii InformerInjector) {
+	r.informers = append(r.informers, ii)
+}
+
+func (r *registry) Informers() []InformerInjector {
+	return r.informers
+}
+
+// Default is the registry that generated informer packages register
+// themselves with.
+var Default Interface = &registry{}